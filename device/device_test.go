@@ -0,0 +1,213 @@
+package device
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name string
+		in   string
+		want map[string]struct{}
+	}{
+		{"empty", "", map[string]struct{}{}},
+		{
+			"ua",
+			"Mozilla/5.0 (iPhone; CPU iPhone OS 14_6 like Mac OS X)",
+			map[string]struct{}{
+				"mozilla": {}, "5.0": {}, "iphone": {}, "cpu": {}, "os": {},
+				"14": {}, "6": {}, "like": {}, "mac": {}, "x": {},
+			},
+		},
+		{"case folds", "CHROME", map[string]struct{}{"chrome": {}}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tokenize(tt.in)
+			if len(got) != len(tt.want) {
+				t.Fatalf("tokenize(%q) = %v, want %v", tt.in, got, tt.want)
+			}
+			for tok := range tt.want {
+				if _, ok := got[tok]; !ok {
+					t.Errorf("tokenize(%q) missing token %q, got %v", tt.in, tok, got)
+				}
+			}
+		})
+	}
+}
+
+func TestDice(t *testing.T) {
+	tests := []struct {
+		name string
+		a, b map[string]struct{}
+		want float64
+	}{
+		{"both empty", map[string]struct{}{}, map[string]struct{}{}, 0},
+		{"one empty", map[string]struct{}{"a": {}}, map[string]struct{}{}, 0},
+		{
+			"identical",
+			map[string]struct{}{"a": {}, "b": {}},
+			map[string]struct{}{"a": {}, "b": {}},
+			1,
+		},
+		{
+			"disjoint",
+			map[string]struct{}{"a": {}},
+			map[string]struct{}{"b": {}},
+			0,
+		},
+		{
+			"half overlap",
+			map[string]struct{}{"a": {}, "b": {}},
+			map[string]struct{}{"a": {}, "c": {}},
+			0.5,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := dice(tt.a, tt.b); got != tt.want {
+				t.Errorf("dice(%v, %v) = %v, want %v", tt.a, tt.b, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestMobileHint(t *testing.T) {
+	tests := []struct {
+		name      string
+		hints     []ClientHint
+		wantOK    bool
+		wantValue bool
+	}{
+		{"absent", nil, false, false},
+		{"mobile true", []ClientHint{{Name: "Sec-CH-UA-Mobile", Value: "?1"}}, true, true},
+		{"mobile false", []ClientHint{{Name: "Sec-CH-UA-Mobile", Value: "?0"}}, true, false},
+		{"case insensitive name", []ClientHint{{Name: "sec-ch-ua-mobile", Value: "?1"}}, true, true},
+		{"other hint", []ClientHint{{Name: "Sec-CH-UA-Platform", Value: `"Android"`}}, false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			value, ok := mobileHint(tt.hints)
+			if ok != tt.wantOK || (ok && value != tt.wantValue) {
+				t.Errorf("mobileHint(%v) = (%v, %v), want (%v, %v)", tt.hints, value, ok, tt.wantValue, tt.wantOK)
+			}
+		})
+	}
+}
+
+func TestHintValue(t *testing.T) {
+	hints := []ClientHint{{Name: "Sec-CH-UA-Platform", Value: `"Android"`}}
+	if v, ok := hintValue(hints, "Sec-CH-UA-Platform"); !ok || v != "Android" {
+		t.Errorf("hintValue() = (%q, %v), want (%q, true)", v, ok, "Android")
+	}
+	if _, ok := hintValue(hints, "Sec-CH-UA-Model"); ok {
+		t.Error("hintValue() found a hint that isn't present")
+	}
+}
+
+func TestDeviceScore(t *testing.T) {
+	d := Info{Mobile: true, Touch: true, ClientHints: &UserAgentMetadata{Platform: "Android", Model: "Pixel 7"}}
+	toks := tokenize(d.UserAgent)
+	in := tokenize("")
+
+	base := deviceScore(in, d, toks, nil)
+
+	withHints := deviceScore(in, d, toks, []ClientHint{
+		{Name: "Sec-CH-UA-Mobile", Value: "?1"},
+		{Name: "Sec-CH-UA-Platform", Value: `"Android"`},
+		{Name: "Sec-CH-UA-Model", Value: "Pixel 7"},
+	})
+	if withHints <= base {
+		t.Errorf("deviceScore with matching hints = %v, want > base %v", withHints, base)
+	}
+
+	withMismatch := deviceScore(in, d, toks, []ClientHint{
+		{Name: "Sec-CH-UA-Mobile", Value: "?0"},
+	})
+	if withMismatch >= base {
+		t.Errorf("deviceScore with mismatched hint = %v, want < base %v", withMismatch, base)
+	}
+}
+
+func TestDetectNoMatch(t *testing.T) {
+	info, score, ok := Detect("")
+	if ok {
+		t.Errorf("Detect(\"\") matched %v with score %v, want no match", info, score)
+	}
+	if info != Reset.Device() {
+		t.Errorf("Detect(\"\") = %v, want the Reset device", info)
+	}
+	if score != 0 {
+		t.Errorf("Detect(\"\") score = %v, want 0", score)
+	}
+}
+
+func TestDetectScoreClamped(t *testing.T) {
+	for i, d := range devices {
+		if d.UserAgent == "" {
+			continue
+		}
+		_, score, _ := Detect(d.UserAgent, ClientHint{Name: "Sec-CH-UA-Mobile", Value: boolHintValue(d.Mobile)})
+		if score > 1 {
+			t.Fatalf("Detect score for devices[%d] = %v, want <= 1", i, score)
+		}
+	}
+}
+
+func boolHintValue(mobile bool) string {
+	if mobile {
+		return "?1"
+	}
+	return "?0"
+}
+
+func TestRegister(t *testing.T) {
+	want := Info{Name: "My Custom Phone", UserAgent: "custom-ua", Width: 400, Height: 800, Mobile: true, Touch: true}
+	typ := Register(want)
+
+	if int(typ) < len(devices) {
+		t.Fatalf("Register() returned Type %d, want an index past the %d built-in devices", typ, len(devices))
+	}
+	if got := typ.Device(); got != want {
+		t.Errorf("typ.Device() = %v, want %v", got, want)
+	}
+	if got := typ.String(); got != want.Name {
+		t.Errorf("typ.String() = %q, want %q", got, want.Name)
+	}
+
+	// a second registration gets its own, distinct Type
+	typ2 := Register(Info{Name: "Another Custom Device"})
+	if typ2 == typ {
+		t.Errorf("Register() returned the same Type %d twice", typ)
+	}
+	if typ2.Device().Name != "Another Custom Device" {
+		t.Errorf("typ2.Device() = %v, want Name %q", typ2.Device(), "Another Custom Device")
+	}
+}
+
+func TestLoadJSON(t *testing.T) {
+	const data = `[
+		{"Name": "JSON Device One", "UserAgent": "ua-one"},
+		{"Name": "JSON Device Two", "UserAgent": "ua-two", "Mobile": true}
+	]`
+	types, err := LoadJSON(strings.NewReader(data))
+	if err != nil {
+		t.Fatalf("LoadJSON() error = %v", err)
+	}
+	if len(types) != 2 {
+		t.Fatalf("LoadJSON() returned %d types, want 2", len(types))
+	}
+	if got := types[0].Device().Name; got != "JSON Device One" {
+		t.Errorf("types[0].Device().Name = %q, want %q", got, "JSON Device One")
+	}
+	if got := types[1].Device(); got.Name != "JSON Device Two" || !got.Mobile {
+		t.Errorf("types[1].Device() = %v, want Name %q and Mobile true", got, "JSON Device Two")
+	}
+}
+
+func TestLoadJSONInvalid(t *testing.T) {
+	if _, err := LoadJSON(strings.NewReader("not json")); err == nil {
+		t.Error("LoadJSON() with invalid JSON returned a nil error")
+	}
+}
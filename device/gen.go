@@ -14,6 +14,7 @@ import (
 	"net/http"
 	"os"
 	"regexp"
+	"sort"
 	"strings"
 )
 
@@ -21,8 +22,9 @@ const deviceDescriptorsURL = "https://raw.githubusercontent.com/puppeteer/puppet
 
 func main() {
 	out := flag.String("out", "device.go", "out")
+	source := flag.String("source", deviceDescriptorsURL, "URL or file:// path to DeviceDescriptors.ts")
 	flag.Parse()
-	if err := run(*out); err != nil {
+	if err := run(*out, *source); err != nil {
 		fmt.Fprintf(os.Stderr, "error: %v\n", err)
 		os.Exit(1)
 	}
@@ -39,26 +41,150 @@ type deviceDescriptor struct {
 		HasTouch          bool    `json:"hasTouch"`
 		IsLandscape       bool    `json:"isLandscape"`
 	} `json:"viewport"`
+	// UserAgentMetadata carries the UA-CH fields Puppeteer ships for a
+	// descriptor, when present. It's nil for most devices, in which case
+	// clientHints synthesizes one from UserAgent and Viewport.IsMobile.
+	UserAgentMetadata *uaMetadata `json:"userAgentMetadata"`
+}
+
+// uaMetadata mirrors the subset of CDP's UserAgentMetadata shape that
+// DeviceDescriptors.ts entries carry.
+type uaMetadata struct {
+	Brands          []brandVersion `json:"brands"`
+	FullVersionList []brandVersion `json:"fullVersionList"`
+	Platform        string         `json:"platform"`
+	PlatformVersion string         `json:"platformVersion"`
+	Architecture    string         `json:"architecture"`
+	Model           string         `json:"model"`
+	Mobile          bool           `json:"mobile"`
+	Bitness         string         `json:"bitness"`
+	Wow64           bool           `json:"wow64"`
+}
+
+// brandVersion is a single browser brand/version pair, as used in
+// uaMetadata.Brands and uaMetadata.FullVersionList.
+type brandVersion struct {
+	Brand   string `json:"brand"`
+	Version string `json:"version"`
 }
 
 var cleanRE = regexp.MustCompile(`[^a-zA-Z0-9_]`)
 
+var (
+	browserVersionRE  = regexp.MustCompile(`(Chrome|CriOS|Firefox|FxiOS|Edg)/([\d.]+)`)
+	safariVersionRE   = regexp.MustCompile(`Version/([\d.]+)`)
+	platformVersionRE = regexp.MustCompile(`(?:Windows NT|Android|CPU(?: iPhone)? OS|Mac OS X) ([\d._]+)`)
+)
+
+// clientHints returns d's UA-CH metadata, taken verbatim from the descriptor
+// when Puppeteer supplies one, or synthesized from UserAgent and
+// Viewport.IsMobile otherwise.
+func clientHints(d deviceDescriptor) *uaMetadata {
+	if d.UserAgentMetadata != nil {
+		return d.UserAgentMetadata
+	}
+	return synthesizeClientHints(d)
+}
+
+// synthesizeClientHints derives a best-effort uaMetadata from a device's
+// UserAgent string and viewport, for the (common) case where Puppeteer
+// doesn't ship one.
+func synthesizeClientHints(d deviceDescriptor) *uaMetadata {
+	m := &uaMetadata{
+		Platform: platformOf(d.UserAgent),
+		Mobile:   d.Viewport.IsMobile,
+		Bitness:  "64",
+	}
+	if v := platformVersionRE.FindStringSubmatch(d.UserAgent); v != nil {
+		m.PlatformVersion = strings.ReplaceAll(v[1], "_", ".")
+	}
+	if d.Viewport.IsMobile {
+		m.Model = d.Name
+	} else {
+		m.Architecture = "x86"
+	}
+	if v := browserVersionRE.FindStringSubmatch(d.UserAgent); v != nil {
+		brand := v[1]
+		if brand == "CriOS" || brand == "Edg" {
+			brand = map[string]string{"CriOS": "Chrome", "Edg": "Microsoft Edge"}[brand]
+		}
+		m.Brands = []brandVersion{{Brand: brand, Version: v[2]}}
+		m.FullVersionList = m.Brands
+	} else if v := safariVersionRE.FindStringSubmatch(d.UserAgent); v != nil {
+		m.Brands = []brandVersion{{Brand: "Safari", Version: v[1]}}
+		m.FullVersionList = m.Brands
+	}
+	return m
+}
+
+// platformOf returns the CDP platform name (e.g. "Windows", "macOS",
+// "Android", "iOS", "Linux") for a User-Agent string.
+func platformOf(ua string) string {
+	switch {
+	case strings.Contains(ua, "Windows"):
+		return "Windows"
+	case strings.Contains(ua, "Android"):
+		return "Android"
+	case strings.Contains(ua, "iPhone"), strings.Contains(ua, "iPad"):
+		return "iOS"
+	case strings.Contains(ua, "Mac OS X"):
+		return "macOS"
+	case strings.Contains(ua, "Linux"):
+		return "Linux"
+	default:
+		return ""
+	}
+}
+
+// genClientHintsExpr renders m as a Go composite literal expression for
+// embedding directly into the generated devices table.
+func genClientHintsExpr(m *uaMetadata) string {
+	if m == nil {
+		return "nil"
+	}
+	buf := new(bytes.Buffer)
+	fmt.Fprint(buf, "&UserAgentMetadata{")
+	fmt.Fprintf(buf, "Brands: %s,", genBrandsExpr(m.Brands))
+	fmt.Fprintf(buf, "FullVersionList: %s,", genBrandsExpr(m.FullVersionList))
+	fmt.Fprintf(buf, "Platform: %q,", m.Platform)
+	fmt.Fprintf(buf, "PlatformVersion: %q,", m.PlatformVersion)
+	fmt.Fprintf(buf, "Architecture: %q,", m.Architecture)
+	fmt.Fprintf(buf, "Model: %q,", m.Model)
+	fmt.Fprintf(buf, "Mobile: %t,", m.Mobile)
+	fmt.Fprintf(buf, "Bitness: %q,", m.Bitness)
+	fmt.Fprintf(buf, "Wow64: %t,", m.Wow64)
+	fmt.Fprint(buf, "}")
+	return buf.String()
+}
+
+// genBrandsExpr renders a []brandVersion as a Go []UserAgentBrandVersion
+// composite literal expression.
+func genBrandsExpr(brands []brandVersion) string {
+	buf := new(bytes.Buffer)
+	fmt.Fprint(buf, "[]UserAgentBrandVersion{")
+	for _, b := range brands {
+		fmt.Fprintf(buf, "{Brand: %q, Version: %q},", b.Brand, b.Version)
+	}
+	fmt.Fprint(buf, "}")
+	return buf.String()
+}
+
 // run runs the program.
-func run(out string) error {
+func run(out, source string) error {
 	var descriptors []deviceDescriptor
-	if err := get(&descriptors); err != nil {
+	if err := get(source, &descriptors); err != nil {
 		return err
 	}
 	// add reset device
 	descriptors = append([]deviceDescriptor{{}}, descriptors...)
 	buf := new(bytes.Buffer)
-	fmt.Fprintf(buf, hdr, deviceDescriptorsURL)
+	fmt.Fprintf(buf, hdr, source)
 	fmt.Fprintln(buf, "\n// Devices.")
 	fmt.Fprintln(buf, "const (")
 	for i, d := range descriptors {
 		if i == 0 {
 			fmt.Fprintln(buf, "// Reset is the reset device.")
-			fmt.Fprintln(buf, "Reset infoType = iota\n")
+			fmt.Fprintln(buf, "Reset Type = iota\n")
 		} else {
 			name := cleanRE.ReplaceAllString(d.Name, "")
 			name = strings.ToUpper(name[0:1]) + name[1:]
@@ -69,14 +195,31 @@ func run(out string) error {
 	fmt.Fprintln(buf, ")\n")
 	fmt.Fprintln(buf, "// devices is the list of devices.")
 	fmt.Fprintln(buf, "var devices = [...]Info{")
-	for _, d := range descriptors {
-		fmt.Fprintf(buf, "{%q, %q, %d, %d, %f, %t, %t, %t},\n",
+	for i, d := range descriptors {
+		hints := "nil"
+		if i != 0 {
+			// the reset device (i == 0) has no Client Hints of its own
+			hints = genClientHintsExpr(clientHints(d))
+		}
+		fmt.Fprintf(buf, "{%q, %q, %d, %d, %f, %t, %t, %t, %s},\n",
 			d.Name, d.UserAgent,
 			d.Viewport.Width, d.Viewport.Height, d.Viewport.DeviceScaleFactor,
 			d.Viewport.IsLandscape, d.Viewport.IsMobile, d.Viewport.HasTouch,
+			hints,
 		)
 	}
 	fmt.Fprintln(buf, "}")
+	fmt.Fprintln(buf, "\n// deviceTokens holds the token sets used by Detect to score a raw User-Agent")
+	fmt.Fprintln(buf, "// string against devices, indexed in the same order as devices.")
+	fmt.Fprintln(buf, "var deviceTokens = [...]map[string]struct{}{")
+	for _, d := range descriptors {
+		fmt.Fprint(buf, "{")
+		for _, tok := range genTokenize(d.UserAgent) {
+			fmt.Fprintf(buf, "%q: {}, ", tok)
+		}
+		fmt.Fprintln(buf, "},")
+	}
+	fmt.Fprintln(buf, "}")
 	src, err := format.Source(buf.Bytes())
 	if err != nil {
 		return err
@@ -84,6 +227,27 @@ func run(out string) error {
 	return ioutil.WriteFile(out, src, 0o644)
 }
 
+var genTokenRE = regexp.MustCompile(`[^a-zA-Z0-9.]+`)
+
+// genTokenize splits s into a sorted, deduplicated set of lowercased
+// alphanumeric tokens. It mirrors the tokenize function emitted into
+// device.go, so that the indices precomputed here line up with the tokens
+// Detect extracts from a UA string at runtime.
+func genTokenize(s string) []string {
+	seen := make(map[string]struct{})
+	for _, f := range genTokenRE.Split(strings.ToLower(s), -1) {
+		if f != "" {
+			seen[f] = struct{}{}
+		}
+	}
+	toks := make([]string, 0, len(seen))
+	for t := range seen {
+		toks = append(toks, t)
+	}
+	sort.Strings(toks)
+	return toks
+}
+
 var (
 	startRE        = regexp.MustCompile(`(?m)^const\s+deviceArray:\s*Device\[\]\s*=\s*\[`)
 	endRE          = regexp.MustCompile(`(?m)^\];`)
@@ -92,23 +256,11 @@ var (
 	fixClosesRE    = regexp.MustCompile(`([\]\}]),\n(\s*[\]\}])`)
 )
 
-// get retrieves and decodes the device descriptors.
-func get(v interface{}) error {
-	req, err := http.NewRequest("GET", deviceDescriptorsURL, nil)
-	if err != nil {
-		return err
-	}
-	// retrieve
-	cl := &http.Client{}
-	res, err := cl.Do(req)
-	if err != nil {
-		return err
-	}
-	defer res.Body.Close()
-	if res.StatusCode != 200 {
-		return fmt.Errorf("got status code %d", res.StatusCode)
-	}
-	buf, err := ioutil.ReadAll(res.Body)
+// get retrieves and decodes the device descriptors from source, which may be
+// an http(s):// URL or a file:// path to a locally cached copy of
+// DeviceDescriptors.ts (for reproducible or offline builds).
+func get(source string, v interface{}) error {
+	buf, err := fetch(source)
 	if err != nil {
 		return err
 	}
@@ -130,6 +282,28 @@ func get(v interface{}) error {
 	return json.Unmarshal(buf, v)
 }
 
+// fetch retrieves the raw contents of source, which may be an http(s):// URL
+// or a file:// path.
+func fetch(source string) ([]byte, error) {
+	if path := strings.TrimPrefix(source, "file://"); path != source {
+		return ioutil.ReadFile(path)
+	}
+	req, err := http.NewRequest("GET", source, nil)
+	if err != nil {
+		return nil, err
+	}
+	cl := &http.Client{}
+	res, err := cl.Do(req)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	if res.StatusCode != 200 {
+		return nil, fmt.Errorf("got status code %d", res.StatusCode)
+	}
+	return ioutil.ReadAll(res.Body)
+}
+
 const hdr = `// Package device contains device emulation definitions for use with chromedp's
 // Emulate action.
 //
@@ -138,6 +312,14 @@ package device
 
 ` + `// Generated by gen.go. DO NOT EDIT.` + `
 
+import (
+	"encoding/json"
+	"io"
+	"regexp"
+	"strings"
+	"sync"
+)
+
 //go:generate go run gen.go
 
 // Info holds device information for use with chromedp.Emulate.
@@ -166,6 +348,11 @@ type Info struct {
 
 	// Touch indicates whether the device has touch enabled.
 	Touch bool
+
+	// ClientHints holds the User-Agent Client Hints metadata to report
+	// alongside UserAgent, for use with Emulation.setUserAgentOverride's
+	// userAgentMetadata parameter.
+	ClientHints *UserAgentMetadata
 }
 
 // String satisfies fmt.Stringer.
@@ -178,17 +365,236 @@ func (i Info) Device() Info {
 	return i
 }
 
-// infoType provides the enumerated device type.
-type infoType int
+// UserAgentMetadata holds the structured User-Agent Client Hints data sent
+// via Emulation.setUserAgentOverride's userAgentMetadata parameter, mirroring
+// navigator.userAgentData and the Sec-CH-UA-* request headers.
+type UserAgentMetadata struct {
+	// Brands lists the brand/significant-version pairs reported by
+	// navigator.userAgentData.brands and the Sec-CH-UA header.
+	Brands []UserAgentBrandVersion
+
+	// FullVersionList lists the brand/full-version pairs reported by
+	// navigator.userAgentData.getHighEntropyValues and the
+	// Sec-CH-UA-Full-Version-List header.
+	FullVersionList []UserAgentBrandVersion
+
+	// Platform is the OS platform, e.g. "Windows", "macOS", "Android".
+	Platform string
+
+	// PlatformVersion is the OS version.
+	PlatformVersion string
+
+	// Architecture is the CPU architecture, e.g. "x86".
+	Architecture string
+
+	// Model is the device model, non-empty only on mobile devices.
+	Model string
+
+	// Mobile indicates whether the device identifies as mobile.
+	Mobile bool
+
+	// Bitness is the CPU bitness, e.g. "64".
+	Bitness string
+
+	// Wow64 indicates whether the browser is running in 32-bit mode on
+	// 64-bit Windows.
+	Wow64 bool
+}
+
+// UserAgentBrandVersion is a single browser brand/version pair, as used in
+// UserAgentMetadata.Brands and UserAgentMetadata.FullVersionList.
+type UserAgentBrandVersion struct {
+	// Brand is the browser brand name, e.g. "Chrome".
+	Brand string
+
+	// Version is the brand's version string.
+	Version string
+}
+
+// Type provides the enumerated device type. Its zero value is Reset, and
+// values 0 up to the number of generated devices identify a built-in device;
+// values returned by Register or LoadJSON identify a device registered at
+// runtime. Callers should accept chromedp.Device rather than Type itself
+// wherever possible.
+type Type int
 
 // String satisfies fmt.Stringer.
-func (i infoType) String() string {
-	return devices[i].String()
+func (i Type) String() string {
+	return i.info().String()
 }
 
 // Device satisfies chromedp.Device.
-func (i infoType) Device() Info {
-	return devices[i]
+func (i Type) Device() Info {
+	return i.info()
+}
+
+// info looks up the Info backing i, whether it names a built-in device or
+// one registered at runtime via Register or LoadJSON.
+func (i Type) info() Info {
+	if int(i) < len(devices) {
+		return devices[i]
+	}
+	registryMu.RLock()
+	defer registryMu.RUnlock()
+	return registry[int(i)-len(devices)]
+}
+
+// registryMu guards registry.
+var registryMu sync.RWMutex
+
+// registry holds devices added at runtime via Register or LoadJSON, indexed
+// starting immediately after the generated devices table.
+var registry []Info
+
+// Register adds info to the set of known devices at runtime and returns a
+// Type that can be used anywhere a built-in device constant (such as
+// IPhoneX) is accepted, without needing an entry in the generated device.go.
+// It's safe for concurrent use.
+func Register(info Info) Type {
+	registryMu.Lock()
+	defer registryMu.Unlock()
+	registry = append(registry, info)
+	return Type(len(devices) + len(registry) - 1)
+}
+
+// LoadJSON decodes a JSON array of Info values from r and registers each of
+// them via Register, returning the assigned Type for every entry in order.
+// This lets downstream users pin a custom device list -- for example one
+// checked into their project -- instead of relying solely on the generated
+// table.
+func LoadJSON(r io.Reader) ([]Type, error) {
+	var infos []Info
+	if err := json.NewDecoder(r).Decode(&infos); err != nil {
+		return nil, err
+	}
+	types := make([]Type, len(infos))
+	for i, info := range infos {
+		types[i] = Register(info)
+	}
+	return types, nil
+}
+
+// ClientHint is a single User-Agent Client Hint value, as sent via the
+// Sec-CH-UA-* family of request headers (for example Sec-CH-UA-Model,
+// Sec-CH-UA-Platform, Sec-CH-UA-Mobile).
+type ClientHint struct {
+	// Name is the Client Hint header name.
+	Name string
+
+	// Value is the Client Hint header value.
+	Value string
+}
+
+// DetectThreshold is the minimum score a candidate must reach for Detect to
+// report it as a match. It defaults to 0.3 but can be tuned by callers that
+// need Detect to be stricter or more permissive.
+var DetectThreshold = 0.3
+
+// detectTokenRE splits a User-Agent string into tokens for Detect, the same
+// way genTokenize does at code-gen time.
+var detectTokenRE = regexp.MustCompile("[^a-zA-Z0-9.]+")
+
+// tokenize splits s into a set of lowercased alphanumeric tokens.
+func tokenize(s string) map[string]struct{} {
+	toks := make(map[string]struct{})
+	for _, f := range detectTokenRE.Split(strings.ToLower(s), -1) {
+		if f != "" {
+			toks[f] = struct{}{}
+		}
+	}
+	return toks
+}
+
+// dice returns the Sørensen-Dice coefficient between two token sets, a value
+// in [0, 1] where 1 means identical sets.
+func dice(a, b map[string]struct{}) float64 {
+	if len(a) == 0 || len(b) == 0 {
+		return 0
+	}
+	var common int
+	for t := range a {
+		if _, ok := b[t]; ok {
+			common++
+		}
+	}
+	return 2 * float64(common) / float64(len(a)+len(b))
+}
+
+// hintValue returns the value of the named Client Hint, with the surrounding
+// quotes Sec-CH-UA-* string hints are sent with trimmed, and whether it was
+// present in hints.
+func hintValue(hints []ClientHint, name string) (value string, ok bool) {
+	for _, h := range hints {
+		if strings.EqualFold(h.Name, name) {
+			return strings.Trim(h.Value, "\""), true
+		}
+	}
+	return "", false
+}
+
+// mobileHint reports the value of the Sec-CH-UA-Mobile client hint, and
+// whether it was present in hints.
+func mobileHint(hints []ClientHint) (mobile, ok bool) {
+	if v, ok := hintValue(hints, "Sec-CH-UA-Mobile"); ok {
+		return v == "?1" || strings.EqualFold(v, "true"), true
+	}
+	return false, false
+}
+
+// deviceScore scores a single candidate device d, whose UserAgent tokenizes
+// to toks, against the tokenized input UA in, refined by hints. The result
+// isn't clamped to [0, 1]; Detect does that once over the winning candidate.
+func deviceScore(in map[string]struct{}, d Info, toks map[string]struct{}, hints []ClientHint) float64 {
+	score := dice(in, toks)
+	if wantMobile, ok := mobileHint(hints); ok {
+		// mobile devices are (near) universally touch-capable, so the
+		// Sec-CH-UA-Mobile hint also informs the Touch signal.
+		if d.Mobile == wantMobile && d.Touch == wantMobile {
+			score += 0.1
+		} else {
+			score -= 0.2
+		}
+	}
+	if wantPlatform, ok := hintValue(hints, "Sec-CH-UA-Platform"); ok && d.ClientHints != nil {
+		if strings.EqualFold(d.ClientHints.Platform, wantPlatform) {
+			score += 0.1
+		} else {
+			score -= 0.2
+		}
+	}
+	if wantModel, ok := hintValue(hints, "Sec-CH-UA-Model"); ok && d.ClientHints != nil && d.ClientHints.Model != "" {
+		if strings.EqualFold(d.ClientHints.Model, wantModel) {
+			score += 0.1
+		} else {
+			score -= 0.2
+		}
+	}
+	return score
+}
+
+// Detect returns the Info in the generated device table whose UserAgent most
+// closely matches userAgent, along with a confidence score in the range [0, 1]. The
+// optional Client Hints (Sec-CH-UA-Mobile, Sec-CH-UA-Platform,
+// Sec-CH-UA-Model) refine scoring using signals that plain UA string matching
+// can get wrong, for example a desktop Chrome UA masquerading as a mobile
+// one. If no candidate scores at or above DetectThreshold, Detect returns the
+// Reset device, a score of 0, and false.
+func Detect(userAgent string, hints ...ClientHint) (Info, float64, bool) {
+	in := tokenize(userAgent)
+
+	best, bestScore := Reset.Device(), 0.0
+	for i, d := range devices {
+		if score := deviceScore(in, d, deviceTokens[i], hints); score > bestScore {
+			best, bestScore = d, score
+		}
+	}
+	if bestScore > 1 {
+		bestScore = 1
+	}
+	if bestScore < DetectThreshold {
+		return Reset.Device(), 0, false
+	}
+	return best, bestScore, true
 }
 
 `
@@ -0,0 +1,75 @@
+//go:build ignore
+// +build ignore
+
+package main
+
+import "testing"
+
+func TestPlatformOf(t *testing.T) {
+	tests := []struct {
+		ua   string
+		want string
+	}{
+		{"Mozilla/5.0 (Windows NT 10.0; Win64; x64)", "Windows"},
+		{"Mozilla/5.0 (Linux; Android 13; Pixel 7)", "Android"},
+		{"Mozilla/5.0 (iPhone; CPU iPhone OS 16_0 like Mac OS X)", "iOS"},
+		{"Mozilla/5.0 (iPad; CPU OS 16_0 like Mac OS X)", "iOS"},
+		{"Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7)", "macOS"},
+		{"Mozilla/5.0 (X11; Linux x86_64)", "Linux"},
+		{"some unrecognized agent", ""},
+	}
+	for _, tt := range tests {
+		if got := platformOf(tt.ua); got != tt.want {
+			t.Errorf("platformOf(%q) = %q, want %q", tt.ua, got, tt.want)
+		}
+	}
+}
+
+func TestSynthesizeClientHints(t *testing.T) {
+	desktop := deviceDescriptor{
+		Name:      "Desktop Chrome",
+		UserAgent: "Mozilla/5.0 (Windows NT 10.0; Win64; x64) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/113.0.0.0 Safari/537.36",
+	}
+	m := synthesizeClientHints(desktop)
+	if m.Platform != "Windows" {
+		t.Errorf("Platform = %q, want %q", m.Platform, "Windows")
+	}
+	if m.Mobile {
+		t.Error("Mobile = true, want false for a desktop UA")
+	}
+	if m.Architecture != "x86" {
+		t.Errorf("Architecture = %q, want %q", m.Architecture, "x86")
+	}
+	if len(m.Brands) != 1 || m.Brands[0].Brand != "Chrome" || m.Brands[0].Version != "113.0.0.0" {
+		t.Errorf("Brands = %v, want a single Chrome/113.0.0.0 entry", m.Brands)
+	}
+
+	mobile := deviceDescriptor{Name: "Pixel 7"}
+	mobile.UserAgent = "Mozilla/5.0 (Linux; Android 13; Pixel 7) AppleWebKit/537.36 (KHTML, like Gecko) Chrome/113.0.0.0 Mobile Safari/537.36"
+	mobile.Viewport.IsMobile = true
+	m = synthesizeClientHints(mobile)
+	if !m.Mobile {
+		t.Error("Mobile = false, want true for a mobile UA")
+	}
+	if m.Model != mobile.Name {
+		t.Errorf("Model = %q, want %q", m.Model, mobile.Name)
+	}
+	if m.Architecture != "" {
+		t.Errorf("Architecture = %q, want empty for a mobile device", m.Architecture)
+	}
+
+	safari := deviceDescriptor{Name: "Desktop Safari"}
+	safari.UserAgent = "Mozilla/5.0 (Macintosh; Intel Mac OS X 10_15_7) AppleWebKit/605.1.15 (KHTML, like Gecko) Version/16.5 Safari/605.1.15"
+	m = synthesizeClientHints(safari)
+	if len(m.Brands) != 1 || m.Brands[0].Brand != "Safari" || m.Brands[0].Version != "16.5" {
+		t.Errorf("Brands = %v, want a single Safari/16.5 entry", m.Brands)
+	}
+}
+
+func TestClientHintsPrefersDescriptor(t *testing.T) {
+	given := &uaMetadata{Platform: "Custom"}
+	d := deviceDescriptor{UserAgentMetadata: given}
+	if got := clientHints(d); got != given {
+		t.Errorf("clientHints() = %v, want the descriptor's own %v", got, given)
+	}
+}
@@ -0,0 +1,91 @@
+package chromedp
+
+import (
+	"context"
+
+	"github.com/chromedp/cdproto/emulation"
+	"github.com/chromedp/chromedp/device"
+)
+
+// EmulateOption is a device emulation option.
+type EmulateOption = func(*emulation.SetDeviceMetricsOverrideParams, *emulation.SetTouchEmulationEnabledParams, *emulation.SetUserAgentOverrideParams)
+
+// EmulateScale sets the device scale to use when overriding the device
+// metrics.
+func EmulateScale(scale float64) EmulateOption {
+	return func(p1 *emulation.SetDeviceMetricsOverrideParams, p2 *emulation.SetTouchEmulationEnabledParams, p3 *emulation.SetUserAgentOverrideParams) {
+		p1.DeviceScaleFactor = scale
+	}
+}
+
+// EmulateOrientation sets the device viewport screen orientation.
+func EmulateOrientation(orientation emulation.OrientationType, angle int64) EmulateOption {
+	return func(p1 *emulation.SetDeviceMetricsOverrideParams, p2 *emulation.SetTouchEmulationEnabledParams, p3 *emulation.SetUserAgentOverrideParams) {
+		p1.ScreenOrientation = &emulation.ScreenOrientation{
+			Type:  orientation,
+			Angle: angle,
+		}
+	}
+}
+
+// WithClientHints overrides the device's Client Hints metadata for this
+// Emulate call, so emulated pages report the given values from
+// navigator.userAgentData and the Sec-CH-UA-* request headers instead of
+// whatever device.Info.ClientHints carries.
+func WithClientHints(m *device.UserAgentMetadata) EmulateOption {
+	return func(p1 *emulation.SetDeviceMetricsOverrideParams, p2 *emulation.SetTouchEmulationEnabledParams, p3 *emulation.SetUserAgentOverrideParams) {
+		p3.UserAgentMetadata = clientHintsMetadata(m)
+	}
+}
+
+// Emulate emulates a specific device.
+func Emulate(d device.Info, opts ...EmulateOption) Action {
+	return ActionFunc(func(ctx context.Context) error {
+		p1 := emulation.SetDeviceMetricsOverride(d.Width, d.Height, d.Scale, d.Mobile)
+		p2 := emulation.SetTouchEmulationEnabled(d.Touch)
+		p3 := emulation.SetUserAgentOverride(d.UserAgent).WithUserAgentMetadata(clientHintsMetadata(d.ClientHints))
+		for _, o := range opts {
+			o(p1, p2, p3)
+		}
+		if err := p1.Do(ctx); err != nil {
+			return err
+		}
+		if err := p2.Do(ctx); err != nil {
+			return err
+		}
+		return p3.Do(ctx)
+	})
+}
+
+// clientHintsMetadata converts m to its cdproto equivalent, returning nil
+// when m is nil so devices without Client Hints fall back to the legacy
+// User-Agent-only override.
+func clientHintsMetadata(m *device.UserAgentMetadata) *emulation.UserAgentMetadata {
+	if m == nil {
+		return nil
+	}
+	return &emulation.UserAgentMetadata{
+		Brands:          brandVersions(m.Brands),
+		FullVersionList: brandVersions(m.FullVersionList),
+		Platform:        m.Platform,
+		PlatformVersion: m.PlatformVersion,
+		Architecture:    m.Architecture,
+		Model:           m.Model,
+		Mobile:          m.Mobile,
+		Bitness:         m.Bitness,
+		Wow64:           m.Wow64,
+	}
+}
+
+// brandVersions converts a []device.UserAgentBrandVersion to its cdproto
+// equivalent.
+func brandVersions(brands []device.UserAgentBrandVersion) []*emulation.UserAgentBrandVersion {
+	if brands == nil {
+		return nil
+	}
+	out := make([]*emulation.UserAgentBrandVersion, len(brands))
+	for i, b := range brands {
+		out[i] = &emulation.UserAgentBrandVersion{Brand: b.Brand, Version: b.Version}
+	}
+	return out
+}